@@ -0,0 +1,340 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Engine abstracts how the provider actually talks to pgmold, so that
+// SchemaResource (and other resources built on top of it) don't need to
+// know whether pgmold is invoked as a subprocess or called in-process as a
+// Go library.
+type Engine interface {
+	Apply(ctx context.Context, req ApplyRequest) (ApplyResult, error)
+	Diff(ctx context.Context, req DiffRequest) (DiffResult, error)
+	Dump(ctx context.Context, req DumpRequest) (DumpResult, error)
+}
+
+// ApplyRequest carries the inputs runPgmoldApply previously built into a
+// CLI argument list directly.
+type ApplyRequest struct {
+	SchemaFile       string
+	DatabaseURL      string
+	TargetSchemas    string
+	AllowDestructive bool
+	ValidateURL      string
+}
+
+// ApplyResult carries the outcome of an apply, including any diagnostics
+// pgmold reported even on success (e.g. warnings about skipped statements).
+type ApplyResult struct {
+	Diagnostics []Diagnostic
+}
+
+// DiffRequest carries the inputs for previewing the SQL pgmold would apply.
+type DiffRequest struct {
+	SchemaFile    string
+	DatabaseURL   string
+	TargetSchemas string
+}
+
+// DiffResult carries the previewed SQL plus any diagnostics pgmold reported
+// while generating it.
+type DiffResult struct {
+	SQL         string
+	Diagnostics []Diagnostic
+}
+
+// DumpRequest carries the inputs for introspecting a live database.
+type DumpRequest struct {
+	DatabaseURL   string
+	TargetSchemas string
+}
+
+// DumpResult carries a live database's current schema, both as SQL DDL and
+// as the structured tables pgmold reports for it.
+type DumpResult struct {
+	SQL    string
+	Tables []DumpTable
+}
+
+// DumpTable, DumpColumn, DumpIndex and DumpConstraint mirror the structured
+// introspection pgmold reports for a single table.
+type DumpTable struct {
+	Name        string
+	Schema      string
+	Columns     []DumpColumn
+	Indexes     []DumpIndex
+	Constraints []DumpConstraint
+}
+
+type DumpColumn struct {
+	Name     string
+	Type     string
+	Nullable bool
+	Default  string
+}
+
+type DumpIndex struct {
+	Name    string
+	Columns string
+	Unique  bool
+}
+
+type DumpConstraint struct {
+	Name       string
+	Type       string
+	Definition string
+}
+
+// DiagnosticSeverity mirrors the severity levels Terraform's diagnostics
+// package distinguishes between.
+type DiagnosticSeverity int
+
+const (
+	DiagnosticSeverityError DiagnosticSeverity = iota
+	DiagnosticSeverityWarning
+)
+
+// Diagnostic is a single structured finding reported by pgmold, analogous to
+// the legacy SDK's gRPC shim translating warnings/errors into
+// proto.Diagnostic entries. Statement is populated when the diagnostic can
+// be attributed to a specific SQL statement in the generated migration; SQL
+// carries the broader migration the statement belongs to, if pgmold
+// reported one.
+type Diagnostic struct {
+	Severity  DiagnosticSeverity
+	Summary   string
+	Detail    string
+	SQL       string
+	Statement string
+}
+
+// execEngine shells out to a pgmold binary on PATH (or at a configured
+// location). It is the default and only engine pgmold versions predating
+// in-process library support can use.
+type execEngine struct {
+	Binary string
+}
+
+func newExecEngine(binary string) *execEngine {
+	return &execEngine{Binary: binary}
+}
+
+func (e *execEngine) Apply(ctx context.Context, req ApplyRequest) (ApplyResult, error) {
+	args := []string{
+		"apply",
+		"--schema", req.SchemaFile,
+		"--database", req.DatabaseURL,
+		"--target-schemas", req.TargetSchemas,
+	}
+
+	if req.AllowDestructive {
+		args = append(args, "--allow-destructive")
+	}
+
+	if req.ValidateURL != "" {
+		args = append(args, "--validate", req.ValidateURL)
+	}
+
+	output, diagnostics, ok, runErr := e.runWithDiagnostics(ctx, args)
+	if runErr != nil {
+		// Only trust the parsed diagnostics in place of runErr when they
+		// actually explain the failure (at least one error-severity entry).
+		// Otherwise a crash, a missing binary, or a failure that only
+		// produced warnings would be swallowed and reported as success.
+		if ok && hasErrorDiagnostic(diagnostics) {
+			return ApplyResult{Diagnostics: diagnostics}, nil
+		}
+		return ApplyResult{}, fmt.Errorf("pgmold apply failed: %w\nOutput: %s", runErr, output)
+	}
+
+	if !ok {
+		// JSON diagnostics could not be parsed (older pgmold binary):
+		// fall back to the combined-output debug log this engine used
+		// before --log-format=json existed.
+		tflog.Debug(ctx, "pgmold apply succeeded", map[string]interface{}{
+			"output": output,
+		})
+		return ApplyResult{}, nil
+	}
+
+	return ApplyResult{Diagnostics: diagnostics}, nil
+}
+
+// hasErrorDiagnostic reports whether any diagnostic is at error severity.
+func hasErrorDiagnostic(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == DiagnosticSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *execEngine) Diff(ctx context.Context, req DiffRequest) (DiffResult, error) {
+	args := []string{
+		"diff",
+		"--schema", req.SchemaFile,
+		"--database", req.DatabaseURL,
+		"--target-schemas", req.TargetSchemas,
+		"--format=sql",
+	}
+
+	// Diff's output is the generated SQL itself (--format=sql), not a
+	// diagnostics stream, so it is read as plain text rather than run
+	// through runWithDiagnostics.
+	output, err := e.run(ctx, args)
+	if err != nil {
+		return DiffResult{}, fmt.Errorf("pgmold diff failed: %w\nOutput: %s", err, output)
+	}
+
+	return DiffResult{SQL: strings.TrimSpace(output)}, nil
+}
+
+func (e *execEngine) Dump(ctx context.Context, req DumpRequest) (DumpResult, error) {
+	sqlArgs := []string{
+		"dump",
+		"--database", req.DatabaseURL,
+		"--target-schemas", req.TargetSchemas,
+		"--format=sql",
+	}
+	sql, err := e.run(ctx, sqlArgs)
+	if err != nil {
+		return DumpResult{}, fmt.Errorf("pgmold dump failed: %w\nOutput: %s", err, sql)
+	}
+
+	jsonArgs := []string{
+		"dump",
+		"--database", req.DatabaseURL,
+		"--target-schemas", req.TargetSchemas,
+		"--format=json",
+	}
+	tablesJSON, err := e.run(ctx, jsonArgs)
+	if err != nil {
+		return DumpResult{}, fmt.Errorf("pgmold dump failed: %w\nOutput: %s", err, tablesJSON)
+	}
+
+	var rawTables []pgmoldDumpTable
+	if err := json.Unmarshal([]byte(tablesJSON), &rawTables); err != nil {
+		return DumpResult{}, fmt.Errorf("failed to parse pgmold dump JSON output: %w", err)
+	}
+
+	tables := make([]DumpTable, 0, len(rawTables))
+	for _, t := range rawTables {
+		table := DumpTable{Name: t.Name, Schema: t.Schema}
+		for _, c := range t.Columns {
+			table.Columns = append(table.Columns, DumpColumn{Name: c.Name, Type: c.Type, Nullable: c.Nullable, Default: c.Default})
+		}
+		for _, i := range t.Indexes {
+			table.Indexes = append(table.Indexes, DumpIndex{Name: i.Name, Columns: i.Columns, Unique: i.Unique})
+		}
+		for _, c := range t.Constraints {
+			table.Constraints = append(table.Constraints, DumpConstraint{Name: c.Name, Type: c.Type, Definition: c.Definition})
+		}
+		tables = append(tables, table)
+	}
+
+	return DumpResult{SQL: strings.TrimSpace(sql), Tables: tables}, nil
+}
+
+// pgmoldDumpTable mirrors the JSON structure emitted by `pgmold dump
+// --format=json`. It is kept unexported since it only exists to decode the
+// subprocess output before translating it into the engine-agnostic DumpTable
+// above.
+type pgmoldDumpTable struct {
+	Name    string `json:"name"`
+	Schema  string `json:"schema"`
+	Columns []struct {
+		Name     string `json:"name"`
+		Type     string `json:"type"`
+		Nullable bool   `json:"nullable"`
+		Default  string `json:"default"`
+	} `json:"columns"`
+	Indexes []struct {
+		Name    string `json:"name"`
+		Columns string `json:"columns"`
+		Unique  bool   `json:"unique"`
+	} `json:"indexes"`
+	Constraints []struct {
+		Name       string `json:"name"`
+		Type       string `json:"type"`
+		Definition string `json:"definition"`
+	} `json:"constraints"`
+}
+
+func (e *execEngine) run(ctx context.Context, args []string) (string, error) {
+	tflog.Debug(ctx, "Running pgmold", map[string]interface{}{
+		"binary": e.Binary,
+		"args":   strings.Join(args, " "),
+	})
+
+	cmd := exec.CommandContext(ctx, e.Binary, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(output), err
+	}
+
+	return string(output), nil
+}
+
+// runWithDiagnostics runs pgmold with --log-format=json and attempts to
+// parse its output as newline-delimited JSON diagnostics. ok is false when
+// parsing fails (e.g. an older pgmold binary that doesn't support
+// --log-format=json yet), signaling callers to fall back to treating output
+// as a single unstructured blob. runErr carries the subprocess's own exit
+// error, if any, independent of whether diagnostics parsed.
+func (e *execEngine) runWithDiagnostics(ctx context.Context, args []string) (output string, diagnostics []Diagnostic, ok bool, runErr error) {
+	args = append(args, "--log-format=json")
+
+	raw, err := e.run(ctx, args)
+	diagnostics, ok = parseNDJSONDiagnostics(raw)
+	return raw, diagnostics, ok, err
+}
+
+// pgmoldLogDiagnostic mirrors a single line of --log-format=json output.
+type pgmoldLogDiagnostic struct {
+	Level     string `json:"level"`
+	Summary   string `json:"summary"`
+	Detail    string `json:"detail"`
+	SQL       string `json:"sql"`
+	Statement string `json:"statement"`
+}
+
+// parseNDJSONDiagnostics parses newline-delimited JSON diagnostics. ok is
+// false if any non-blank line fails to parse, meaning the output should be
+// treated as unstructured text instead (e.g. a pgmold binary predating
+// --log-format=json).
+func parseNDJSONDiagnostics(output string) (diagnostics []Diagnostic, ok bool) {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		var raw pgmoldLogDiagnostic
+		if err := json.Unmarshal([]byte(line), &raw); err != nil {
+			return nil, false
+		}
+
+		severity := DiagnosticSeverityError
+		if strings.EqualFold(raw.Level, "warning") {
+			severity = DiagnosticSeverityWarning
+		}
+
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity:  severity,
+			Summary:   raw.Summary,
+			Detail:    raw.Detail,
+			SQL:       raw.SQL,
+			Statement: raw.Statement,
+		})
+	}
+
+	return diagnostics, true
+}
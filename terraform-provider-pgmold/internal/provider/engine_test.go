@@ -0,0 +1,59 @@
+package provider
+
+import "testing"
+
+func TestParseNDJSONDiagnostics(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		wantOK     bool
+		wantDiags  int
+		wantHasErr bool
+	}{
+		{
+			name:   "empty output parses as zero diagnostics",
+			output: "",
+			wantOK: true,
+		},
+		{
+			name:       "single error diagnostic",
+			output:     `{"level":"error","summary":"cannot drop column","detail":"column is referenced by a view","statement":"ALTER TABLE foo DROP COLUMN bar;"}`,
+			wantOK:     true,
+			wantDiags:  1,
+			wantHasErr: true,
+		},
+		{
+			name: "warning-only diagnostics report ok with no error severity",
+			output: `{"level":"warning","summary":"index will be rebuilt"}
+{"level":"warning","summary":"statement is a no-op"}`,
+			wantOK:    true,
+			wantDiags: 2,
+		},
+		{
+			name:   "plain text output from an older pgmold binary is not parseable",
+			output: "pgmold: connection refused",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics, ok := parseNDJSONDiagnostics(tt.output)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if len(diagnostics) != tt.wantDiags {
+				t.Fatalf("len(diagnostics) = %d, want %d", len(diagnostics), tt.wantDiags)
+			}
+			if got := hasErrorDiagnostic(diagnostics); got != tt.wantHasErr {
+				t.Fatalf("hasErrorDiagnostic = %v, want %v", got, tt.wantHasErr)
+			}
+		})
+	}
+}
+
+func TestHasErrorDiagnosticEmpty(t *testing.T) {
+	if hasErrorDiagnostic(nil) {
+		t.Fatal("hasErrorDiagnostic(nil) = true, want false")
+	}
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/fmguerreiro/pgmold/pkg/pgmold"
+)
+
+// libraryEngine calls pgmold's Go packages directly instead of shelling out
+// to a separate binary. This avoids a fork per plan/apply, is not
+// PATH-dependent, and lets pgmold's own diagnostics flow straight into
+// Terraform's diagnostics rather than being scraped out of combined
+// stdout+stderr.
+type libraryEngine struct{}
+
+func newLibraryEngine() *libraryEngine {
+	return &libraryEngine{}
+}
+
+func (e *libraryEngine) Apply(ctx context.Context, req ApplyRequest) (ApplyResult, error) {
+	result, err := pgmold.Apply(ctx, pgmold.ApplyOptions{
+		SchemaFile:       req.SchemaFile,
+		DatabaseURL:      req.DatabaseURL,
+		TargetSchemas:    strings.Split(req.TargetSchemas, ","),
+		AllowDestructive: req.AllowDestructive,
+		ValidateURL:      req.ValidateURL,
+	})
+	if err != nil {
+		return ApplyResult{}, err
+	}
+
+	return ApplyResult{Diagnostics: translatePgmoldDiagnostics(result.Diagnostics)}, nil
+}
+
+func (e *libraryEngine) Diff(ctx context.Context, req DiffRequest) (DiffResult, error) {
+	result, err := pgmold.Diff(ctx, pgmold.DiffOptions{
+		SchemaFile:    req.SchemaFile,
+		DatabaseURL:   req.DatabaseURL,
+		TargetSchemas: strings.Split(req.TargetSchemas, ","),
+	})
+	if err != nil {
+		return DiffResult{}, err
+	}
+
+	return DiffResult{
+		SQL:         result.SQL,
+		Diagnostics: translatePgmoldDiagnostics(result.Diagnostics),
+	}, nil
+}
+
+func (e *libraryEngine) Dump(ctx context.Context, req DumpRequest) (DumpResult, error) {
+	result, err := pgmold.Dump(ctx, pgmold.DumpOptions{
+		DatabaseURL:   req.DatabaseURL,
+		TargetSchemas: strings.Split(req.TargetSchemas, ","),
+	})
+	if err != nil {
+		return DumpResult{}, err
+	}
+
+	tables := make([]DumpTable, 0, len(result.Tables))
+	for _, t := range result.Tables {
+		table := DumpTable{Name: t.Name, Schema: t.Schema}
+		for _, c := range t.Columns {
+			table.Columns = append(table.Columns, DumpColumn{Name: c.Name, Type: c.Type, Nullable: c.Nullable, Default: c.Default})
+		}
+		for _, i := range t.Indexes {
+			table.Indexes = append(table.Indexes, DumpIndex{Name: i.Name, Columns: i.Columns, Unique: i.Unique})
+		}
+		for _, c := range t.Constraints {
+			table.Constraints = append(table.Constraints, DumpConstraint{Name: c.Name, Type: c.Type, Definition: c.Definition})
+		}
+		tables = append(tables, table)
+	}
+
+	return DumpResult{SQL: result.SQL, Tables: tables}, nil
+}
+
+// translatePgmoldDiagnostics converts pgmold's own diagnostic type into the
+// provider's Engine-agnostic Diagnostic, so SchemaResource never needs to
+// import pgmold directly.
+func translatePgmoldDiagnostics(in []pgmold.Diagnostic) []Diagnostic {
+	out := make([]Diagnostic, 0, len(in))
+	for _, d := range in {
+		severity := DiagnosticSeverityError
+		if d.Severity == pgmold.SeverityWarning {
+			severity = DiagnosticSeverityWarning
+		}
+
+		out = append(out, Diagnostic{
+			Severity:  severity,
+			Summary:   d.Summary,
+			Detail:    d.Detail,
+			Statement: d.Statement,
+		})
+	}
+
+	return out
+}
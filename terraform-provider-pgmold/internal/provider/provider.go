@@ -2,11 +2,15 @@ package provider
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -18,6 +22,7 @@ type PgmoldProvider struct {
 
 type PgmoldProviderModel struct {
 	PgmoldBinary types.String `tfsdk:"pgmold_binary"`
+	Mode         types.String `tfsdk:"mode"`
 }
 
 func New(version string) func() provider.Provider {
@@ -38,9 +43,16 @@ func (p *PgmoldProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 		Description: "Terraform provider for pgmold - PostgreSQL schema-as-code tool",
 		Attributes: map[string]schema.Attribute{
 			"pgmold_binary": schema.StringAttribute{
-				Description: "Path to the pgmold binary. Defaults to 'pgmold' (assumes it's in PATH).",
+				Description: "Path to the pgmold binary. Defaults to 'pgmold' (assumes it's in PATH). Only used when mode is \"exec\".",
 				Optional:    true,
 			},
+			"mode": schema.StringAttribute{
+				Description: "How the provider talks to pgmold: \"exec\" (default) forks the pgmold binary for every operation; \"library\" calls pgmold's Go packages in-process.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("exec", "library"),
+				},
+			},
 		},
 	}
 }
@@ -57,8 +69,30 @@ func (p *PgmoldProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		pgmoldBinary = config.PgmoldBinary.ValueString()
 	}
 
+	mode := "exec"
+	if !config.Mode.IsNull() {
+		mode = config.Mode.ValueString()
+	}
+
+	var engine Engine
+	switch mode {
+	case "library":
+		engine = newLibraryEngine()
+	case "exec":
+		engine = newExecEngine(pgmoldBinary)
+	default:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("mode"),
+			"Invalid mode",
+			fmt.Sprintf("mode must be \"exec\" or \"library\", got %q", mode),
+		)
+		return
+	}
+
 	providerData := &ProviderData{
 		PgmoldBinary: pgmoldBinary,
+		Mode:         mode,
+		Engine:       engine,
 	}
 
 	resp.DataSourceData = providerData
@@ -68,13 +102,18 @@ func (p *PgmoldProvider) Configure(ctx context.Context, req provider.ConfigureRe
 func (p *PgmoldProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewSchemaResource,
+		NewMigrationResource,
 	}
 }
 
 func (p *PgmoldProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewSchemaDataSource,
+	}
 }
 
 type ProviderData struct {
 	PgmoldBinary string
+	Mode         string
+	Engine       Engine
 }
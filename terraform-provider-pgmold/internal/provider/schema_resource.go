@@ -6,10 +6,11 @@ import (
 	"encoding/hex"
 	"fmt"
 	"os"
-	"os/exec"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -25,8 +26,14 @@ var (
 	_ resource.Resource                = &SchemaResource{}
 	_ resource.ResourceWithConfigure   = &SchemaResource{}
 	_ resource.ResourceWithImportState = &SchemaResource{}
+	_ resource.ResourceWithModifyPlan  = &SchemaResource{}
 )
 
+// destructiveStatementPattern matches SQL statements that would drop or
+// truncate data. It is used to block plans that would otherwise apply
+// destructive changes silently when allow_destructive is false.
+var destructiveStatementPattern = regexp.MustCompile(`(?im)^\s*(DROP\s+TABLE|DROP\s+COLUMN|ALTER\s+[^;]*\bDROP\b|TRUNCATE)\b.*$`)
+
 type SchemaResource struct {
 	providerData *ProviderData
 }
@@ -40,6 +47,10 @@ type SchemaResourceModel struct {
 	ValidateURL      types.String `tfsdk:"validate_url"`
 	SchemaHash       types.String `tfsdk:"schema_hash"`
 	LastApplied      types.String `tfsdk:"last_applied"`
+	PlannedSQL       types.String `tfsdk:"planned_sql"`
+	DetectDrift      types.Bool   `tfsdk:"detect_drift"`
+	DriftDetected    types.Bool   `tfsdk:"drift_detected"`
+	DriftSQL         types.String `tfsdk:"drift_sql"`
 }
 
 func NewSchemaResource() resource.Resource {
@@ -94,10 +105,111 @@ func (r *SchemaResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Computed:    true,
 				Description: "Timestamp of the last successful apply operation.",
 			},
+			"planned_sql": schema.StringAttribute{
+				Computed:    true,
+				Description: "The SQL that pgmold would execute to reconcile the database with schema_file, as previewed during terraform plan. Empty when no changes are pending.",
+			},
+			"detect_drift": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Whether to compare the live database against schema_file during refresh. Introspecting on every refresh can be expensive on large schemas; set to false to skip it.",
+			},
+			"drift_detected": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the live database has diverged from schema_file, as found during the most recent refresh.",
+			},
+			"drift_sql": schema.StringAttribute{
+				Computed:    true,
+				Description: "The SQL needed to reconcile the live database back to schema_file, as found during the most recent refresh. Empty when no drift was detected.",
+			},
 		},
 	}
 }
 
+// ModifyPlan previews the migration pgmold would apply by running the
+// configured Engine's Diff (`pgmold diff` in exec mode) whenever an attribute
+// that affects the generated SQL has changed. The resulting SQL is surfaced via planned_sql so operators can
+// review it in `terraform plan` output instead of discovering it at apply
+// time. When allow_destructive is false, the previewed SQL is scanned for
+// destructive statements and the plan is rejected if any are found.
+func (r *SchemaResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		// Destroy plan: nothing to preview.
+		return
+	}
+
+	var plan SchemaResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state SchemaResourceModel
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	schemaContent, err := os.ReadFile(plan.SchemaFile.ValueString())
+	if err != nil {
+		// Let Create/Update surface the read error; ModifyPlan should not
+		// fail the plan over a file that may not exist yet in CI contexts.
+		// planned_sql must still be set to a known value here, or it stays
+		// Unknown in the outgoing plan and trips Terraform's post-apply
+		// consistency check once Create/Update apply without ever setting it.
+		plan.PlannedSQL = types.StringValue("")
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+		return
+	}
+	hash := sha256.Sum256(schemaContent)
+	schemaHash := hex.EncodeToString(hash[:])
+
+	changed := req.State.Raw.IsNull() ||
+		schemaHash != state.SchemaHash.ValueString() ||
+		plan.TargetSchemas.ValueString() != state.TargetSchemas.ValueString() ||
+		plan.DatabaseURL.ValueString() != state.DatabaseURL.ValueString() ||
+		plan.AllowDestructive.ValueBool() != state.AllowDestructive.ValueBool()
+
+	if !changed {
+		plan.PlannedSQL = types.StringValue("")
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+		return
+	}
+
+	diffResult, err := r.providerData.Engine.Diff(ctx, DiffRequest{
+		SchemaFile:    plan.SchemaFile.ValueString(),
+		DatabaseURL:   plan.DatabaseURL.ValueString(),
+		TargetSchemas: plan.TargetSchemas.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to preview schema changes", err.Error())
+		return
+	}
+	addEngineDiagnostics(&resp.Diagnostics, diffResult.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sql := diffResult.SQL
+
+	if !plan.AllowDestructive.ValueBool() {
+		if offending := destructiveStatementPattern.FindAllString(sql, -1); len(offending) > 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("allow_destructive"),
+				"Plan contains destructive statements",
+				fmt.Sprintf("The following statements would be executed but allow_destructive is false:\n\n%s", strings.Join(offending, "\n")),
+			)
+			return
+		}
+	}
+
+	plan.PlannedSQL = types.StringValue(sql)
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
 func (r *SchemaResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -131,14 +243,15 @@ func (r *SchemaResource) Create(ctx context.Context, req resource.CreateRequest,
 	hash := sha256.Sum256(schemaContent)
 	schemaHash := hex.EncodeToString(hash[:])
 
-	if err := r.runPgmoldApply(ctx, &plan); err != nil {
-		resp.Diagnostics.AddError("Failed to apply schema", err.Error())
+	if !r.applySchema(ctx, &plan, &resp.Diagnostics) {
 		return
 	}
 
 	plan.ID = types.StringValue(fmt.Sprintf("%s:%s", plan.DatabaseURL.ValueString(), plan.TargetSchemas.ValueString()))
 	plan.SchemaHash = types.StringValue(schemaHash)
 	plan.LastApplied = types.StringValue(currentTimestamp())
+	plan.DriftDetected = types.BoolValue(false)
+	plan.DriftSQL = types.StringValue("")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
@@ -161,9 +274,52 @@ func (r *SchemaResource) Read(ctx context.Context, req resource.ReadRequest, res
 		state.SchemaHash = types.StringValue(hex.EncodeToString(hash[:]))
 	}
 
+	detectDrift := true
+	if !state.DetectDrift.IsNull() {
+		detectDrift = state.DetectDrift.ValueBool()
+	}
+
+	if detectDrift {
+		r.detectDrift(ctx, &state, &resp.Diagnostics)
+	} else {
+		state.DriftDetected = types.BoolValue(false)
+		state.DriftSQL = types.StringValue("")
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
+// detectDrift compares the live database against schema_file by running the
+// Engine's Diff and populates drift_detected/drift_sql on state. A detected
+// drift also invalidates schema_hash so the hash comparisons in ModifyPlan
+// and Update treat schema_file as changed: the next plan previews the
+// corrective SQL and the following apply runs it, even though schema_file
+// itself hasn't changed. Diff failures are
+// reported as warnings rather than errors so a flaky database connection
+// during refresh doesn't block `terraform plan` entirely.
+func (r *SchemaResource) detectDrift(ctx context.Context, state *SchemaResourceModel, diagnostics *diag.Diagnostics) {
+	diffResult, err := r.providerData.Engine.Diff(ctx, DiffRequest{
+		SchemaFile:    state.SchemaFile.ValueString(),
+		DatabaseURL:   state.DatabaseURL.ValueString(),
+		TargetSchemas: state.TargetSchemas.ValueString(),
+	})
+	if err != nil {
+		diagnostics.AddWarning("Failed to detect drift", err.Error())
+		return
+	}
+
+	driftSQL := strings.TrimSpace(diffResult.SQL)
+	state.DriftDetected = types.BoolValue(driftSQL != "")
+	state.DriftSQL = types.StringValue(driftSQL)
+
+	if driftSQL != "" {
+		tflog.Info(ctx, "Drift detected between live database and schema_file", map[string]interface{}{
+			"database_url": state.DatabaseURL.ValueString(),
+		})
+		state.SchemaHash = types.StringValue("")
+	}
+}
+
 func (r *SchemaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var plan SchemaResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -187,11 +343,12 @@ func (r *SchemaResource) Update(ctx context.Context, req resource.UpdateRequest,
 	newHash := hex.EncodeToString(hash[:])
 
 	if newHash != state.SchemaHash.ValueString() || plan.DatabaseURL.ValueString() != state.DatabaseURL.ValueString() {
-		if err := r.runPgmoldApply(ctx, &plan); err != nil {
-			resp.Diagnostics.AddError("Failed to apply schema", err.Error())
+		if !r.applySchema(ctx, &plan, &resp.Diagnostics) {
 			return
 		}
 		plan.LastApplied = types.StringValue(currentTimestamp())
+		plan.DriftDetected = types.BoolValue(false)
+		plan.DriftSQL = types.StringValue("")
 	} else {
 		plan.LastApplied = state.LastApplied
 	}
@@ -210,38 +367,44 @@ func (r *SchemaResource) ImportState(ctx context.Context, req resource.ImportSta
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
-func (r *SchemaResource) runPgmoldApply(ctx context.Context, model *SchemaResourceModel) error {
-	args := []string{
-		"apply",
-		"--schema", model.SchemaFile.ValueString(),
-		"--database", model.DatabaseURL.ValueString(),
-		"--target-schemas", model.TargetSchemas.ValueString(),
-	}
-
-	if model.AllowDestructive.ValueBool() {
-		args = append(args, "--allow-destructive")
-	}
-
-	if !model.ValidateURL.IsNull() && model.ValidateURL.ValueString() != "" {
-		args = append(args, "--validate", model.ValidateURL.ValueString())
-	}
-
-	tflog.Debug(ctx, "Running pgmold", map[string]interface{}{
-		"binary": r.providerData.PgmoldBinary,
-		"args":   strings.Join(args, " "),
+// applySchema runs the configured Engine's Apply and reports any failure or
+// diagnostics onto diagnostics. It returns false if the caller should abort
+// (either the engine returned an error, or it reported diagnostics at error
+// severity).
+func (r *SchemaResource) applySchema(ctx context.Context, model *SchemaResourceModel, diagnostics *diag.Diagnostics) bool {
+	result, err := r.providerData.Engine.Apply(ctx, ApplyRequest{
+		SchemaFile:       model.SchemaFile.ValueString(),
+		DatabaseURL:      model.DatabaseURL.ValueString(),
+		TargetSchemas:    model.TargetSchemas.ValueString(),
+		AllowDestructive: model.AllowDestructive.ValueBool(),
+		ValidateURL:      model.ValidateURL.ValueString(),
 	})
-
-	cmd := exec.CommandContext(ctx, r.providerData.PgmoldBinary, args...)
-	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("pgmold apply failed: %w\nOutput: %s", err, string(output))
+		diagnostics.AddError("Failed to apply schema", err.Error())
+		return false
 	}
 
-	tflog.Debug(ctx, "pgmold apply succeeded", map[string]interface{}{
-		"output": string(output),
-	})
+	addEngineDiagnostics(diagnostics, result.Diagnostics)
+	return !diagnostics.HasError()
+}
 
-	return nil
+// addEngineDiagnostics translates Engine diagnostics into Terraform
+// diagnostics, attributing statement-level findings to schema_file rather
+// than surfacing a single combined-output error string.
+func addEngineDiagnostics(diagnostics *diag.Diagnostics, engineDiagnostics []Diagnostic) {
+	for _, d := range engineDiagnostics {
+		detail := d.Detail
+		if d.Statement != "" {
+			detail = fmt.Sprintf("%s\n\nStatement:\n%s", detail, d.Statement)
+		}
+
+		switch d.Severity {
+		case DiagnosticSeverityWarning:
+			diagnostics.AddAttributeWarning(path.Root("schema_file"), d.Summary, detail)
+		default:
+			diagnostics.AddAttributeError(path.Root("schema_file"), d.Summary, detail)
+		}
+	}
 }
 
 func currentTimestamp() string {
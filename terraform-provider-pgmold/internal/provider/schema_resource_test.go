@@ -0,0 +1,28 @@
+package provider
+
+import "testing"
+
+func TestDestructiveStatementPattern(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		want      bool
+	}{
+		{name: "drop table", statement: "DROP TABLE users;", want: true},
+		{name: "drop column", statement: "ALTER TABLE users DROP COLUMN email;", want: true},
+		{name: "truncate", statement: "TRUNCATE orders;", want: true},
+		{name: "drop constraint via alter", statement: "ALTER TABLE orders DROP CONSTRAINT orders_pkey;", want: true},
+		{name: "lowercase drop table", statement: "drop table users;", want: true},
+		{name: "create table is not destructive", statement: "CREATE TABLE users (id int);", want: false},
+		{name: "add column is not destructive", statement: "ALTER TABLE users ADD COLUMN nickname text;", want: false},
+		{name: "insert is not destructive", statement: "INSERT INTO users (id) VALUES (1);", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := destructiveStatementPattern.MatchString(tt.statement); got != tt.want {
+				t.Fatalf("MatchString(%q) = %v, want %v", tt.statement, got, tt.want)
+			}
+		})
+	}
+}